@@ -0,0 +1,246 @@
+package bandcamp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// activityStreamsContext is the JSON-LD context every ActivityStreams 2.0 document must declare.
+const activityStreamsContext = "https://www.w3.org/ns/activitystreams"
+
+// asPageSize is the number of items rendered per OrderedCollectionPage. It intentionally matches
+// ListerConfig's default BatchSize so a page maps onto one underlying API fetch.
+const asPageSize = 40
+
+// orderedCollection is the root ActivityStreams document for a fan's wishlist or collection. It
+// only ever has totalItems, first, and last populated; the items themselves live on the pages.
+type orderedCollection struct {
+	Context    string `json:"@context"`
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	TotalItems int    `json:"totalItems"`
+	First      string `json:"first"`
+	Last       string `json:"last"`
+}
+
+// orderedCollectionPage is one page of items within an orderedCollection.
+type orderedCollectionPage struct {
+	Context      string       `json:"@context"`
+	ID           string       `json:"id"`
+	Type         string       `json:"type"`
+	PartOf       string       `json:"partOf"`
+	Next         string       `json:"next,omitempty"`
+	Prev         string       `json:"prev,omitempty"`
+	OrderedItems []asActivity `json:"orderedItems"`
+}
+
+// asActivity is the ActivityStreams object built from an Item. Albums are rendered as Audio
+// objects; anything else (tracks, merch) falls back to a generic Document.
+type asActivity struct {
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	Name         string `json:"name"`
+	AttributedTo string `json:"attributedTo"`
+	Published    string `json:"published"`
+}
+
+// itemToActivity converts an Item into its ActivityStreams representation. Item carries no band URL
+// or title of its own, so both are derived from ItemURL: the band's own Bandcamp page is the host
+// the item lives under, and the name is recovered from the URL's slug.
+func itemToActivity(item Item) asActivity {
+	asType := "Document"
+	if item.ItemType == "album" || item.ItemType == "track" {
+		asType = "Audio"
+	}
+	return asActivity{
+		ID:           item.ItemURL,
+		Type:         asType,
+		Name:         titleFromItemURL(item.ItemURL),
+		AttributedTo: bandURLFromItemURL(item.ItemURL),
+		Published:    item.Added,
+	}
+}
+
+// bandURLFromItemURL returns the band's own Bandcamp page, e.g. "https://spaceband.bandcamp.com",
+// derived from an item's URL.
+func bandURLFromItemURL(itemURL string) string {
+	u, err := url.Parse(itemURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// titleFromItemURL recovers a human-readable name from an item's URL slug, e.g.
+// ".../album/midnight-drive" -> "Midnight Drive".
+func titleFromItemURL(itemURL string) string {
+	u, err := url.Parse(itemURL)
+	if err != nil {
+		return itemURL
+	}
+	slug := strings.Trim(u.Path, "/")
+	if idx := strings.LastIndex(slug, "/"); idx != -1 {
+		slug = slug[idx+1:]
+	}
+	if slug == "" {
+		return itemURL
+	}
+	words := strings.Split(slug, "-")
+	for i, word := range words {
+		if word == "" {
+			continue
+		}
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// activityPubCollection is a named collection of items (a wishlist or a purchased collection)
+// served as an ActivityStreams feed at collectionPath.
+type activityPubCollection struct {
+	name           string // "wishlist" or "collection"
+	collectionPath string // e.g. "/users/space-llama/wishlist"
+	items          []Item
+}
+
+// collectionHandler serves the root OrderedCollection document.
+func (c *activityPubCollection) collectionHandler(w http.ResponseWriter, r *http.Request) {
+	pages := (len(c.items) + asPageSize - 1) / asPageSize
+	doc := orderedCollection{
+		Context:    activityStreamsContext,
+		ID:         c.collectionPath,
+		Type:       "OrderedCollection",
+		TotalItems: len(c.items),
+		First:      pagePath(c.collectionPath, 1),
+		Last:       pagePath(c.collectionPath, pages),
+	}
+	writeJSON(w, doc)
+}
+
+// pageHandler serves an individual OrderedCollectionPage. Pages are 1-indexed and map directly onto
+// the site's own older_than_token-driven pagination: page N holds the items a Sequential Lister
+// would have returned by its Nth NextBatch call.
+func (c *activityPubCollection) pageHandler(w http.ResponseWriter, r *http.Request, page int) {
+	start := (page - 1) * asPageSize
+	if start < 0 || start >= len(c.items) {
+		http.NotFound(w, r)
+		return
+	}
+	end := start + asPageSize
+	if end > len(c.items) {
+		end = len(c.items)
+	}
+
+	asItems := make([]asActivity, 0, end-start)
+	for _, item := range c.items[start:end] {
+		asItems = append(asItems, itemToActivity(item))
+	}
+
+	doc := orderedCollectionPage{
+		Context:      activityStreamsContext,
+		ID:           pagePath(c.collectionPath, page),
+		Type:         "OrderedCollectionPage",
+		PartOf:       c.collectionPath,
+		OrderedItems: asItems,
+	}
+	if end < len(c.items) {
+		doc.Next = pagePath(c.collectionPath, page+1)
+	}
+	if page > 1 {
+		doc.Prev = pagePath(c.collectionPath, page-1)
+	}
+	writeJSON(w, doc)
+}
+
+func pagePath(collectionPath string, page int) string {
+	return collectionPath + "?page=" + strconv.Itoa(page)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/activity+json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// newActivityPubMux builds the HTTP routes for the "serve" subcommand: OrderedCollection and paged
+// OrderedCollectionPage documents for a fan's wishlist and purchased collection. wishlist and
+// collection must already hold every item, not just the first page.
+func newActivityPubMux(fan string, wishlist, collection []Item) *http.ServeMux {
+	feeds := []*activityPubCollection{
+		{name: "wishlist", collectionPath: "/users/" + fan + "/wishlist", items: wishlist},
+		{name: "collection", collectionPath: "/users/" + fan + "/collection", items: collection},
+	}
+
+	mux := http.NewServeMux()
+	for _, feed := range feeds {
+		feed := feed
+		mux.HandleFunc(feed.collectionPath, func(w http.ResponseWriter, r *http.Request) {
+			if page := r.URL.Query().Get("page"); page != "" {
+				n, err := strconv.Atoi(page)
+				if err != nil || n < 1 {
+					http.Error(w, "invalid page", http.StatusBadRequest)
+					return
+				}
+				feed.pageHandler(w, r, n)
+				return
+			}
+			feed.collectionHandler(w, r)
+		})
+	}
+	return mux
+}
+
+// ServeActivityPub walks fan's wishlist and collection to completion via client (the same
+// worksteal-backed Lister used elsewhere in the package, so this scales to the thousands of items a
+// large wishlist can hold) and serves them as ActivityPub OrderedCollection documents on addr until
+// the process is killed or ctx is done.
+func ServeActivityPub(ctx context.Context, client *Client, fan, addr string) error {
+	wishlist, err := drainItems(ctx, client.Wishlist, fan)
+	if err != nil {
+		return err
+	}
+	collection, err := drainItems(ctx, client.Collection, fan)
+	if err != nil {
+		return err
+	}
+
+	mux := newActivityPubMux(fan, wishlist, collection)
+	fmt.Printf("serving ActivityPub feeds for %s on %s\n", fan, addr)
+
+	server := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	return server.ListenAndServe()
+}
+
+// drainItems walks an ItemIterator (as produced by Client.Wishlist or Client.Collection) to
+// completion and returns every Item it yielded.
+func drainItems(ctx context.Context, open func(context.Context, string) (*ItemIterator, error), fan string) ([]Item, error) {
+	it, err := open(ctx, fan)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []Item
+	for {
+		item, err := it.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
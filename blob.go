@@ -1,14 +1,6 @@
-package main
-
-import (
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"net/http"
-	"regexp"
-	"strconv"
-	"strings"
-)
+// Package bandcamp is a client for the unofficial JSON APIs behind bandcamp.com fan pages: wishlist,
+// purchased collection, followers, following, and the activity feed.
+package bandcamp
 
 // DataBlob is a rendered JSON blob that's returned on the initial page fetch. Subsequent fetches
 // for data are done using POST requests to their API; urls look like
@@ -81,74 +73,19 @@ type Item struct {
 	ItemType string `json:"item_type"`
 }
 
-func GetWishlist(fanID, lastpageToken string) (APIItemsResponse, error) {
-	request := map[string]string{
-		"fan_id":           fanID,
-		"older_than_token": lastpageToken,
-	}
-	requestJSON, _ := json.Marshal(request)
-
-	resp, err := http.Post("https://bandcamp.com/api/fancollection/1/wishlist_items", "application/json", strings.NewReader(string(requestJSON)))
-	if err != nil {
-		return APIItemsResponse{}, err
-	}
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Println(err)
-		return APIItemsResponse{}, err
-	}
-
-	var response APIItemsResponse
-	if err = json.Unmarshal(body, &response); err != nil {
-		return APIItemsResponse{}, err
-	}
-
-	return response, nil
+// FanSummary is one entry in a Followers or Following listing.
+type FanSummary struct {
+	FanID    int    `json:"fan_id"`
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	ImageURL string `json:"image_url"`
 }
 
-func main() {
-	resp, err := http.Get("https://bandcamp.com/space-llama/wishlist")
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
-
-	// Extract the baked-in datablob in the HTML
-	datablobExp := regexp.MustCompile("id=\"pagedata\".*?data-blob=\"(.*?)\">")
-	datablobMatch := datablobExp.FindStringSubmatch(string(body))
-	pagedata := strings.ReplaceAll(datablobMatch[1], "&quot;", "\"")
-
-	// Unmarshal the datablob
-	var datablob DataBlob
-	if err := json.Unmarshal([]byte(pagedata), &datablob); err != nil {
-		fmt.Println(err)
-		return
-	}
-
-	for _, trackID := range datablob.WishlistData.Sequence {
-		for itemcacheID, item := range datablob.ItemCache.Wishlist {
-			if trackID == itemcacheID {
-				fmt.Println(item.ItemURL)
-				break
-			}
-		}
-	}
-
-	nextPage, err := GetWishlist(strconv.Itoa(datablob.FanData.ID), datablob.WishlistData.LastToken)
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
-
-	for _, item := range nextPage.Items {
-		fmt.Println(item.ItemURL)
-	}
+// FeedStory is one entry in a fan's activity Feed: a purchase, a follow, or a post by someone
+// they follow.
+type FeedStory struct {
+	StoryID int    `json:"story_id"`
+	Date    string `json:"date"`
+	Story   string `json:"story_type"`
+	ItemURL string `json:"item_url"`
 }
@@ -0,0 +1,195 @@
+package bandcamp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Client is a Bandcamp fan-page client. Its http.Client is cookiejar-backed so that Login (or
+// ImportCookie) lets subsequent requests see private collections and the activity feed the same
+// way a logged-in browser would.
+type Client struct {
+	HTTP *http.Client
+
+	// FanID is the numeric fan id resolved by Login, ImportCookie, or the first call that needs
+	// one (Wishlist, Collection, Followers, Following, Feed all resolve it lazily from the fan's
+	// page if it isn't set yet).
+	FanID int
+}
+
+// NewClient builds an unauthenticated Client. Call Login or ImportCookie before using Feed, or
+// before calling Wishlist/Collection on a private account.
+func NewClient() (*Client, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{HTTP: &http.Client{Jar: jar}}, nil
+}
+
+// Login signs in with a Bandcamp username and password, populating the Client's cookie jar and
+// FanID.
+func (c *Client) Login(ctx context.Context, username, password string) error {
+	crumb, err := c.loginCrumb(ctx)
+	if err != nil {
+		return fmt.Errorf("bandcamp: fetching login page: %w", err)
+	}
+
+	form := url.Values{
+		"username": {username},
+		"password": {password},
+		"crumb":    {crumb},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://bandcamp.com/login_cb", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf("bandcamp: login request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var loginResp struct {
+		Ok    bool   `json:"ok"`
+		Error string `json:"error"`
+		FanID int    `json:"fan_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&loginResp); err != nil {
+		return fmt.Errorf("bandcamp: decoding login response: %w", err)
+	}
+	if !loginResp.Ok {
+		return fmt.Errorf("bandcamp: login failed: %s", loginResp.Error)
+	}
+	c.FanID = loginResp.FanID
+	return nil
+}
+
+// loginCrumb fetches bandcamp.com/login and pulls the CSRF crumb out of its pagedata blob, which
+// login_cb requires alongside the credentials.
+func (c *Client) loginCrumb(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://bandcamp.com/login", nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	blob, err := parsePageData(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(blob.FanData.ID), nil
+}
+
+// ImportCookie authenticates the Client using an already-logged-in session cookie (the value of
+// the "identity" cookie from a browser session), for accounts where scripting the login form isn't
+// desirable.
+func (c *Client) ImportCookie(identity string) {
+	jar := c.HTTP.Jar
+	u, _ := url.Parse("https://bandcamp.com")
+	jar.SetCookies(u, []*http.Cookie{{Name: "identity", Value: identity, Domain: ".bandcamp.com", Path: "/"}})
+}
+
+// fetchFanBlob fetches fan's page and returns its pagedata DataBlob.
+func (c *Client) fetchFanBlob(ctx context.Context, fan, page string) (DataBlob, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://bandcamp.com/"+fan+"/"+page, nil)
+	if err != nil {
+		return DataBlob{}, err
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return DataBlob{}, err
+	}
+	defer resp.Body.Close()
+	return parsePageData(resp.Body)
+}
+
+// Wishlist returns an iterator over fan's wishlist, ordered by Added descending. It's backed by a
+// Lister configured for Open pagination, so the first batch races Sequential against WorkSteal.
+func (c *Client) Wishlist(ctx context.Context, fan string) (*ItemIterator, error) {
+	return c.itemIterator(ctx, fan, "wishlist", "https://bandcamp.com/api/fancollection/1/wishlist_items")
+}
+
+// Collection returns an iterator over fan's purchased collection, ordered by Added descending.
+func (c *Client) Collection(ctx context.Context, fan string) (*ItemIterator, error) {
+	return c.itemIterator(ctx, fan, "collection", "https://bandcamp.com/api/fancollection/1/collection_items")
+}
+
+func (c *Client) itemIterator(ctx context.Context, fan, page, endpoint string) (*ItemIterator, error) {
+	blob, err := c.fetchFanBlob(ctx, fan, page)
+	if err != nil {
+		return nil, err
+	}
+
+	var seed map[string]Item
+	var data ItemData
+	if page == "wishlist" {
+		seed, data = blob.ItemCache.Wishlist, blob.WishlistData
+	} else {
+		seed, data = blob.ItemCache.Collection, blob.CollectionData
+	}
+
+	first := itemsInOrder(data, seed)
+	lister := NewLister(strconv.Itoa(blob.FanData.ID), endpoint, seed, first, data.LastToken, ListerConfig{})
+	return newItemIterator(lister, first), nil
+}
+
+// Followers returns an iterator over accounts following fan.
+func (c *Client) Followers(ctx context.Context, fan string) (*FanIterator, error) {
+	return c.fanIterator(ctx, fan, "https://bandcamp.com/api/fancollection/1/followers")
+}
+
+// Following returns an iterator over accounts fan follows.
+func (c *Client) Following(ctx context.Context, fan string) (*FanIterator, error) {
+	return c.fanIterator(ctx, fan, "https://bandcamp.com/api/fancollection/1/following_bands")
+}
+
+func (c *Client) fanIterator(ctx context.Context, fan, endpoint string) (*FanIterator, error) {
+	blob, err := c.fetchFanBlob(ctx, fan, "followers")
+	if err != nil {
+		return nil, err
+	}
+	return &FanIterator{
+		client:    c,
+		fanID:     strconv.Itoa(blob.FanData.ID),
+		endpoint:  endpoint,
+		lastToken: "",
+	}, nil
+}
+
+// Feed returns an iterator over fan's activity feed (purchases, follows, and posts by accounts
+// they follow). It requires an authenticated Client.
+func (c *Client) Feed(ctx context.Context, fan string) (*FeedIterator, error) {
+	blob, err := c.fetchFanBlob(ctx, fan, "")
+	if err != nil {
+		return nil, err
+	}
+	return &FeedIterator{client: c, fanID: strconv.Itoa(blob.FanData.ID)}, nil
+}
+
+// itemsInOrder walks ItemData's Sequence (falling back to PendingSequence) against its ItemCache.
+func itemsInOrder(data ItemData, cache map[string]Item) []Item {
+	sequence := data.Sequence
+	if len(sequence) == 0 {
+		sequence = data.PendingSequence
+	}
+	items := make([]Item, 0, len(sequence))
+	for _, id := range sequence {
+		if item, ok := cache[id]; ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,67 @@
+// Command bandcamp is a CLI front-end for the bandcamp package: printing a fan's wishlist, or
+// serving it as an ActivityPub feed.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ox/bandcamp"
+	"github.com/ox/bandcamp/export"
+)
+
+func main() {
+	fan := flag.String("fan", "space-llama", "fan whose wishlist to fetch")
+	serve := flag.Bool("serve", false, "serve the fan's wishlist/collection as ActivityPub instead of printing it")
+	format := flag.String("format", "", "export format: json, m3u, rss, atom, or opml (default: print item URLs)")
+	flag.Parse()
+
+	ctx := context.Background()
+	client, err := bandcamp.NewClient()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if *serve {
+		if err := bandcamp.ServeActivityPub(ctx, client, *fan, ":8080"); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	it, err := client.Wishlist(ctx, *fan)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	var items []bandcamp.Item
+	for {
+		item, err := it.Next(ctx)
+		if err != nil {
+			break
+		}
+		items = append(items, item)
+	}
+
+	if *format == "" {
+		for _, item := range items {
+			fmt.Println(item.ItemURL)
+		}
+		return
+	}
+
+	exporter := export.ForFormat(*format, *fan+"'s wishlist", "https://bandcamp.com/"+*fan+"/wishlist", client.HTTP)
+	if exporter == nil {
+		fmt.Printf("unknown -format %q\n", *format)
+		os.Exit(1)
+	}
+	if err := exporter.Export(os.Stdout, items); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
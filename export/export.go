@@ -0,0 +1,289 @@
+// Package export turns a fetched wishlist or collection into formats other tools understand:
+// newline-delimited JSON, an M3U playlist, an RSS/Atom feed, or an OPML outline grouped by band.
+package export
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/ox/bandcamp"
+)
+
+// previewURLExp matches the streamable MP3 preview URL embedded in an album or track page's
+// trackinfo blob, the same way subsonic.handleStream resolves one for a single item.
+var previewURLExp = regexp.MustCompile(`"mp3-128":"(https:[^"]+)"`)
+
+// resolveStreamURL fetches itemURL (an album or track page) and extracts its streamable MP3
+// preview URL. client defaults to http.DefaultClient if nil.
+func resolveStreamURL(client *http.Client, itemURL string) (string, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Get(itemURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	page, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	match := previewURLExp.FindSubmatch(page)
+	if match == nil {
+		return "", fmt.Errorf("export: no stream URL found on %s", itemURL)
+	}
+	return string(match[1]), nil
+}
+
+// Exporter writes a sequence of Items to w in some downstream-friendly format.
+type Exporter interface {
+	Export(w io.Writer, items []bandcamp.Item) error
+}
+
+// bandAndTitle pulls the band name and a title out of an ItemURL like
+// "https://spaceband.bandcamp.com/album/some-album", since Item itself carries no separate title
+// or band fields.
+func bandAndTitle(itemURL string) (band, title string) {
+	u, err := url.Parse(itemURL)
+	if err != nil {
+		return "", itemURL
+	}
+	band = strings.TrimSuffix(u.Hostname(), ".bandcamp.com")
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) > 0 {
+		title = strings.ReplaceAll(parts[len(parts)-1], "-", " ")
+	}
+	return band, title
+}
+
+// JSONExporter writes items as newline-delimited JSON, one Item per line.
+type JSONExporter struct{}
+
+func (JSONExporter) Export(w io.Writer, items []bandcamp.Item) error {
+	enc := json.NewEncoder(w)
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// M3UExporter writes items as an extended M3U8 playlist, with #EXTINF band/title metadata ahead of
+// each stream URL.
+type M3UExporter struct {
+	// HTTPClient is used to resolve each item's streamable preview URL. Defaults to
+	// http.DefaultClient if nil.
+	HTTPClient *http.Client
+}
+
+func (e M3UExporter) Export(w io.Writer, items []bandcamp.Item) error {
+	if _, err := io.WriteString(w, "#EXTM3U\n"); err != nil {
+		return err
+	}
+	for _, item := range items {
+		streamURL, err := resolveStreamURL(e.HTTPClient, item.ItemURL)
+		if err != nil {
+			return err
+		}
+		band, title := bandAndTitle(item.ItemURL)
+		if _, err := fmt.Fprintf(w, "#EXTINF:-1,%s - %s\n%s\n", band, title, streamURL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FeedFormat selects the syndication format a FeedExporter writes.
+type FeedFormat int
+
+const (
+	RSS FeedFormat = iota
+	Atom
+)
+
+// FeedExporter writes items as an RSS 2.0 or Atom feed, with pubDate/updated taken from
+// Item.Added and an enclosure/link pointing at the item's streamable MP3 preview.
+type FeedExporter struct {
+	Format FeedFormat
+	Title  string
+	Link   string
+
+	// HTTPClient is used to resolve each item's streamable preview URL. Defaults to
+	// http.DefaultClient if nil.
+	HTTPClient *http.Client
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title string    `xml:"title"`
+	Link  string    `xml:"link"`
+	Items []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title     string       `xml:"title"`
+	Link      string       `xml:"link"`
+	PubDate   string       `xml:"pubDate"`
+	Enclosure rssEnclosure `xml:"enclosure"`
+}
+
+type rssEnclosure struct {
+	URL  string `xml:"url,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	Title   string      `xml:"title"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title   string     `xml:"title"`
+	Links   []atomLink `xml:"link"`
+	Updated string     `xml:"updated"`
+	ID      string     `xml:"id"`
+}
+
+func (e FeedExporter) Export(w io.Writer, items []bandcamp.Item) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	if e.Format == Atom {
+		feed := atomFeed{
+			Xmlns: "http://www.w3.org/2005/Atom",
+			Title: e.Title,
+			Link:  atomLink{Href: e.Link},
+		}
+		for _, item := range items {
+			streamURL, err := resolveStreamURL(e.HTTPClient, item.ItemURL)
+			if err != nil {
+				return err
+			}
+			_, title := bandAndTitle(item.ItemURL)
+			feed.Entries = append(feed.Entries, atomEntry{
+				Title: title,
+				Links: []atomLink{
+					{Href: item.ItemURL},
+					{Href: streamURL, Rel: "enclosure", Type: "audio/mpeg"},
+				},
+				Updated: item.Added,
+				ID:      item.ItemURL,
+			})
+		}
+		return xml.NewEncoder(w).Encode(feed)
+	}
+
+	feed := rssFeed{Version: "2.0", Channel: rssChannel{Title: e.Title, Link: e.Link}}
+	for _, item := range items {
+		streamURL, err := resolveStreamURL(e.HTTPClient, item.ItemURL)
+		if err != nil {
+			return err
+		}
+		_, title := bandAndTitle(item.ItemURL)
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:     title,
+			Link:      item.ItemURL,
+			PubDate:   item.Added,
+			Enclosure: rssEnclosure{URL: streamURL, Type: "audio/mpeg"},
+		})
+	}
+	return xml.NewEncoder(w).Encode(feed)
+}
+
+// OPMLExporter writes items as an OPML outline, grouped by band, for import into podcast/feed
+// readers.
+type OPMLExporter struct {
+	Title string
+}
+
+type opmlDoc struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    opmlHead `xml:"head"`
+	Body    opmlBody `xml:"body"`
+}
+
+type opmlHead struct {
+	Title string `xml:"title"`
+}
+
+type opmlBody struct {
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Outlines []opmlOutline `xml:"outline,omitempty"`
+	XMLURL   string        `xml:"xmlUrl,attr,omitempty"`
+}
+
+func (e OPMLExporter) Export(w io.Writer, items []bandcamp.Item) error {
+	byBand := map[string][]bandcamp.Item{}
+	var bandOrder []string
+	for _, item := range items {
+		band, _ := bandAndTitle(item.ItemURL)
+		if _, ok := byBand[band]; !ok {
+			bandOrder = append(bandOrder, band)
+		}
+		byBand[band] = append(byBand[band], item)
+	}
+
+	doc := opmlDoc{Version: "2.0", Head: opmlHead{Title: e.Title}}
+	for _, band := range bandOrder {
+		group := opmlOutline{Text: band}
+		for _, item := range byBand[band] {
+			_, title := bandAndTitle(item.ItemURL)
+			group.Outlines = append(group.Outlines, opmlOutline{Text: title, XMLURL: item.ItemURL})
+		}
+		doc.Body.Outlines = append(doc.Body.Outlines, group)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(doc)
+}
+
+// ForFormat resolves the -format flag value to an Exporter, or nil if the format is unrecognized.
+// httpClient is used by formats that need to resolve a streamable preview URL (m3u, rss, atom); it
+// may be nil to fall back to http.DefaultClient.
+func ForFormat(format, title, link string, httpClient *http.Client) Exporter {
+	switch format {
+	case "json":
+		return JSONExporter{}
+	case "m3u":
+		return M3UExporter{HTTPClient: httpClient}
+	case "rss":
+		return FeedExporter{Format: RSS, Title: title, Link: link, HTTPClient: httpClient}
+	case "atom":
+		return FeedExporter{Format: Atom, Title: title, Link: link, HTTPClient: httpClient}
+	case "opml":
+		return OPMLExporter{Title: title}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,177 @@
+package bandcamp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ItemIterator walks a wishlist or collection page by page, via a Lister, transparently fetching
+// more items as Next is called.
+type ItemIterator struct {
+	lister *Lister
+	buf    []Item
+	done   bool
+}
+
+func newItemIterator(lister *Lister, first []Item) *ItemIterator {
+	return &ItemIterator{lister: lister, buf: first}
+}
+
+// Next returns the next Item, fetching another batch from the Lister if the current one is
+// exhausted. It returns io.EOF once the wishlist/collection has been fully walked.
+func (it *ItemIterator) Next(ctx context.Context) (Item, error) {
+	for len(it.buf) == 0 {
+		if it.done {
+			return Item{}, io.EOF
+		}
+		resp, err := it.lister.NextBatch(ctx)
+		if err != nil {
+			return Item{}, err
+		}
+		it.buf = resp.Items
+		if !resp.MoreAvailable {
+			it.done = true
+		}
+	}
+	item := it.buf[0]
+	it.buf = it.buf[1:]
+	return item, nil
+}
+
+// FanIterator walks a Followers or Following listing page by page.
+type FanIterator struct {
+	client *Client
+
+	fanID     string
+	endpoint  string
+	lastToken string
+	done      bool
+	buf       []FanSummary
+}
+
+// Next returns the next FanSummary, fetching another page once the buffer is drained. It returns
+// io.EOF once the listing is exhausted.
+func (it *FanIterator) Next(ctx context.Context) (FanSummary, error) {
+	for len(it.buf) == 0 {
+		if it.done {
+			return FanSummary{}, io.EOF
+		}
+		page, err := it.fetch(ctx)
+		if err != nil {
+			return FanSummary{}, err
+		}
+		it.buf = page.Items
+		it.lastToken = page.LastToken
+		if !page.MoreAvailable {
+			it.done = true
+		}
+	}
+	fan := it.buf[0]
+	it.buf = it.buf[1:]
+	return fan, nil
+}
+
+type fanPage struct {
+	Items         []FanSummary `json:"items"`
+	MoreAvailable bool         `json:"more_available"`
+	LastToken     string       `json:"last_token"`
+}
+
+func (it *FanIterator) fetch(ctx context.Context) (fanPage, error) {
+	request := map[string]string{
+		"fan_id":           it.fanID,
+		"older_than_token": it.lastToken,
+	}
+	requestJSON, err := json.Marshal(request)
+	if err != nil {
+		return fanPage{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, it.endpoint, strings.NewReader(string(requestJSON)))
+	if err != nil {
+		return fanPage{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := it.client.HTTP.Do(req)
+	if err != nil {
+		return fanPage{}, err
+	}
+	defer resp.Body.Close()
+
+	var page fanPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return fanPage{}, err
+	}
+	return page, nil
+}
+
+// FeedIterator walks a fan's activity feed page by page.
+type FeedIterator struct {
+	client *Client
+
+	fanID     string
+	lastToken string
+	done      bool
+	buf       []FeedStory
+}
+
+// Next returns the next FeedStory, fetching another page once the buffer is drained. It returns
+// io.EOF once the feed is exhausted.
+func (it *FeedIterator) Next(ctx context.Context) (FeedStory, error) {
+	for len(it.buf) == 0 {
+		if it.done {
+			return FeedStory{}, io.EOF
+		}
+		page, err := it.fetch(ctx)
+		if err != nil {
+			return FeedStory{}, err
+		}
+		it.buf = page.Stories
+		it.lastToken = page.LastToken
+		if !page.MoreAvailable {
+			it.done = true
+		}
+	}
+	story := it.buf[0]
+	it.buf = it.buf[1:]
+	return story, nil
+}
+
+type feedPage struct {
+	Stories       []FeedStory `json:"stories"`
+	MoreAvailable bool        `json:"more_available"`
+	LastToken     string      `json:"last_token"`
+}
+
+func (it *FeedIterator) fetch(ctx context.Context) (feedPage, error) {
+	request := map[string]string{
+		"fan_id":     it.fanID,
+		"older_than": it.lastToken,
+	}
+	requestJSON, err := json.Marshal(request)
+	if err != nil {
+		return feedPage{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://bandcamp.com/api/fan/2/feed_older", strings.NewReader(string(requestJSON)))
+	if err != nil {
+		return feedPage{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := it.client.HTTP.Do(req)
+	if err != nil {
+		return feedPage{}, err
+	}
+	defer resp.Body.Close()
+
+	var page feedPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return feedPage{}, err
+	}
+	return page, nil
+}
@@ -0,0 +1,355 @@
+package bandcamp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// addedLayout is the timestamp format Bandcamp uses for Item.Added, e.g. "02 Jan 2006 15:04:05 GMT".
+const addedLayout = "02 Jan 2006 15:04:05 GMT"
+
+// parseAdded parses an Item.Added timestamp, returning the zero time if it doesn't match
+// addedLayout. Comparing the raw strings lexicographically doesn't match calendar order (month
+// abbreviations don't sort alphabetically the way months run), so anything ordering Added values
+// should compare the parsed time.Time instead.
+func parseAdded(added string) time.Time {
+	t, err := time.Parse(addedLayout, added)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// ListMethod selects which pagination strategy a Lister uses to walk a wishlist or collection.
+type ListMethod int
+
+const (
+	// Open races Sequential against WorkSteal on the first batch and keeps whichever wins. This is
+	// the default.
+	Open ListMethod = iota
+	// Sequential walks older_than_token strictly one page at a time, the same way GetWishlist does.
+	Sequential
+	// WorkSteal probes several older_than_token ranges concurrently, trading extra requests for
+	// lower wall-clock time on large wishlists and collections.
+	WorkSteal
+)
+
+func (m ListMethod) String() string {
+	switch m {
+	case Sequential:
+		return "sequential"
+	case WorkSteal:
+		return "worksteal"
+	default:
+		return "open"
+	}
+}
+
+// ListerConfig configures a Lister's pagination behavior.
+type ListerConfig struct {
+	// Method picks the pagination strategy. Open (the zero value) races Sequential against
+	// WorkSteal on the first batch and sticks with whichever wins for subsequent batches.
+	Method ListMethod
+
+	// MaxParallelism bounds how many worksteal probes run in flight at once. Defaults to 4.
+	MaxParallelism int
+
+	// BatchSize is the number of items requested per probe. Defaults to 40, matching the page size
+	// Bandcamp itself bakes into the initial pagedata blob.
+	BatchSize int
+}
+
+func (c ListerConfig) withDefaults() ListerConfig {
+	if c.MaxParallelism <= 0 {
+		c.MaxParallelism = 4
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 40
+	}
+	return c
+}
+
+// Lister walks a fan's wishlist (or, with a different endpoint, their purchased collection),
+// transparently paginating with older_than_token. On the first call to NextBatch it can run a
+// Sequential paginator and a WorkSteal paginator side by side and keep whichever finishes first;
+// every call after that uses only the winning strategy.
+type Lister struct {
+	FanID    string
+	Endpoint string
+
+	cfg ListerConfig
+
+	mu        sync.Mutex
+	decided   bool
+	method    ListMethod
+	lastToken string
+	seeds     []string
+	seen      map[string]bool
+}
+
+// NewLister builds a Lister for the given fan and endpoint (e.g.
+// "https://bandcamp.com/api/fancollection/1/wishlist_items"). seed should be the ItemCache from the
+// fan's initial pagedata blob; its Added timestamps are used to pick older_than_token ranges for the
+// worksteal probes. first and lastToken are the items and pagination cursor already baked into that
+// same pagedata blob (ItemData.Sequence/PendingSequence and ItemData.LastToken) — they're recorded
+// up front so the Lister's own first NextBatch call picks up where the blob left off instead of
+// refetching and re-yielding items the caller already has.
+func NewLister(fanID, endpoint string, seed map[string]Item, first []Item, lastToken string, cfg ListerConfig) *Lister {
+	l := &Lister{
+		FanID:     fanID,
+		Endpoint:  endpoint,
+		cfg:       cfg.withDefaults(),
+		lastToken: lastToken,
+		seen:      make(map[string]bool),
+	}
+	for _, item := range first {
+		l.seen[item.ItemURL] = true
+	}
+	for _, item := range seed {
+		l.seeds = append(l.seeds, item.Added)
+	}
+	sort.Slice(l.seeds, func(i, j int) bool {
+		return parseAdded(l.seeds[i]).After(parseAdded(l.seeds[j]))
+	})
+	return l
+}
+
+// NextBatch fetches the next page of items, ordered by Added descending. On the first call, if the
+// Lister is configured with Open, both strategies are launched under ctx and the loser is canceled
+// as soon as the winner returns.
+func (l *Lister) NextBatch(ctx context.Context) (APIItemsResponse, error) {
+	l.mu.Lock()
+	decided, method := l.decided, l.method
+	l.mu.Unlock()
+
+	if decided {
+		raw, err := l.runStrategy(ctx, method)
+		return l.commit(raw, err)
+	}
+
+	if l.cfg.Method != Open {
+		raw, err := l.runStrategy(ctx, l.cfg.Method)
+		l.decide(l.cfg.Method)
+		return l.commit(raw, err)
+	}
+
+	return l.race(ctx)
+}
+
+// rawBatch is a fetch result before it's been reconciled against the Lister's shared seen-set and
+// lastToken, i.e. before it's known to be the winning candidate of a race.
+type rawBatch struct {
+	Items         []Item
+	LastToken     string
+	MoreAvailable bool
+}
+
+func (l *Lister) runStrategy(ctx context.Context, method ListMethod) (rawBatch, error) {
+	l.mu.Lock()
+	token := l.lastToken
+	l.mu.Unlock()
+
+	if method == Sequential {
+		return l.sequentialFetch(ctx, token)
+	}
+	return l.workStealFetch(ctx, token)
+}
+
+type raceResult struct {
+	method ListMethod
+	raw    rawBatch
+	err    error
+}
+
+// race runs the sequential and worksteal strategies in an errgroup under shared cancelable
+// contexts; whichever returns first wins, and its cancel func kills the other. Only the winner's
+// batch is reconciled against the Lister's shared state (via commit) — the loser's items never touch
+// l.seen or l.lastToken, even if it goes on to finish its fetch after the winner has already been
+// returned to the caller.
+func (l *Lister) race(ctx context.Context) (APIItemsResponse, error) {
+	l.mu.Lock()
+	token := l.lastToken
+	l.mu.Unlock()
+
+	seqCtx, cancelSeq := context.WithCancel(ctx)
+	wsCtx, cancelWS := context.WithCancel(ctx)
+
+	results := make(chan raceResult, 2)
+	g, _ := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		raw, err := l.sequentialFetch(seqCtx, token)
+		results <- raceResult{Sequential, raw, err}
+		return nil
+	})
+	g.Go(func() error {
+		raw, err := l.workStealFetch(wsCtx, token)
+		results <- raceResult{WorkSteal, raw, err}
+		return nil
+	})
+
+	winner := <-results
+	if winner.method == Sequential {
+		cancelWS()
+	} else {
+		cancelSeq()
+	}
+
+	// Drain the loser in the background so its goroutine doesn't leak; its result is discarded.
+	go func() {
+		g.Wait()
+		cancelSeq()
+		cancelWS()
+	}()
+
+	l.decide(winner.method)
+	return l.commit(winner.raw, winner.err)
+}
+
+func (l *Lister) decide(method ListMethod) {
+	l.mu.Lock()
+	if !l.decided {
+		l.decided = true
+		l.method = method
+	}
+	l.mu.Unlock()
+}
+
+// commit reconciles a raw fetch result against the Lister's shared seen-set and lastToken, then sorts
+// the surviving items by Added descending. It must only ever be called with the winning candidate of
+// a race — a losing candidate's rawBatch should be discarded unreconciled.
+func (l *Lister) commit(raw rawBatch, err error) (APIItemsResponse, error) {
+	if err != nil {
+		return APIItemsResponse{}, err
+	}
+
+	l.mu.Lock()
+	out := raw.Items[:0]
+	for _, item := range raw.Items {
+		if l.seen[item.ItemURL] {
+			continue
+		}
+		l.seen[item.ItemURL] = true
+		out = append(out, item)
+	}
+	l.lastToken = raw.LastToken
+	l.mu.Unlock()
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return parseAdded(out[i].Added).After(parseAdded(out[j].Added))
+	})
+
+	return APIItemsResponse{Items: out, LastToken: raw.LastToken, MoreAvailable: raw.MoreAvailable}, nil
+}
+
+func (l *Lister) sequentialFetch(ctx context.Context, token string) (rawBatch, error) {
+	resp, err := l.fetch(ctx, token)
+	if err != nil {
+		return rawBatch{}, err
+	}
+	return rawBatch{Items: resp.Items, LastToken: resp.LastToken, MoreAvailable: resp.MoreAvailable}, nil
+}
+
+// workStealFetch fires off up to MaxParallelism probes in flight, each seeded with an
+// older_than_token derived from an Added timestamp already seen in the blob, and merges the
+// responses. token is used as the older_than_token for a sequential fallback once the seed pool is
+// exhausted. It does not touch the Lister's shared seen-set or lastToken — that's the caller's job,
+// once it's known this is the winning candidate.
+func (l *Lister) workStealFetch(ctx context.Context, token string) (rawBatch, error) {
+	l.mu.Lock()
+	tokens := l.nextSeedTokensLocked()
+	l.mu.Unlock()
+
+	if len(tokens) == 0 {
+		return l.sequentialFetch(ctx, token)
+	}
+
+	var mu sync.Mutex
+	var merged []Item
+	var lastToken string
+
+	g, gctx := errgroup.WithContext(ctx)
+	for _, probeToken := range tokens {
+		probeToken := probeToken
+		g.Go(func() error {
+			resp, err := l.fetch(gctx, probeToken)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			merged = append(merged, resp.Items...)
+			if resp.LastToken > lastToken {
+				lastToken = resp.LastToken
+			}
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return rawBatch{}, err
+	}
+
+	return rawBatch{Items: merged, LastToken: lastToken, MoreAvailable: true}, nil
+}
+
+// nextSeedTokensLocked pops up to MaxParallelism candidate older_than_tokens off l.seeds. Callers
+// must hold l.mu.
+func (l *Lister) nextSeedTokensLocked() []string {
+	n := l.cfg.MaxParallelism
+	if n > len(l.seeds) {
+		n = len(l.seeds)
+	}
+	tokens := make([]string, n)
+	for i := 0; i < n; i++ {
+		tokens[i] = olderThanTokenFor(l.seeds[i])
+	}
+	l.seeds = l.seeds[n:]
+	return tokens
+}
+
+// olderThanTokenFor builds a plausible older_than_token for the given Added timestamp. Bandcamp's
+// real tokens are opaque, but in practice they're keyed off the unix timestamp of the item they
+// point at, so reusing it as a probe is enough to land the API response in roughly the right range.
+func olderThanTokenFor(added string) string {
+	t := parseAdded(added)
+	if t.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%d::a::", t.Unix())
+}
+
+func (l *Lister) fetch(ctx context.Context, olderThanToken string) (APIItemsResponse, error) {
+	request := map[string]string{
+		"fan_id":           l.FanID,
+		"older_than_token": olderThanToken,
+	}
+	requestJSON, err := json.Marshal(request)
+	if err != nil {
+		return APIItemsResponse{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.Endpoint, strings.NewReader(string(requestJSON)))
+	if err != nil {
+		return APIItemsResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return APIItemsResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	var response APIItemsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return APIItemsResponse{}, err
+	}
+	return response, nil
+}
@@ -0,0 +1,89 @@
+package bandcamp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestItemIteratorNoDuplicatesAcrossBatches drains a Lister-backed ItemIterator across more than one
+// batch from a fake wishlist_items endpoint and checks no ItemURL is ever yielded twice — in
+// particular the first server batch deliberately repeats items already present in the blob's first
+// page, the way Bandcamp's own older_than_token=="" response does.
+func TestItemIteratorNoDuplicatesAcrossBatches(t *testing.T) {
+	first := []Item{
+		{ItemURL: "https://a.bandcamp.com/album/one", Added: "03 Jan 2024 00:00:00 GMT"},
+		{ItemURL: "https://b.bandcamp.com/album/two", Added: "02 Jan 2024 00:00:00 GMT"},
+	}
+	batch2 := []Item{
+		{ItemURL: "https://a.bandcamp.com/album/one", Added: "03 Jan 2024 00:00:00 GMT"}, // repeat of first batch
+		{ItemURL: "https://c.bandcamp.com/album/three", Added: "01 Jan 2024 00:00:00 GMT"},
+	}
+	batch3 := []Item{
+		{ItemURL: "https://d.bandcamp.com/album/four", Added: "31 Dec 2023 00:00:00 GMT"},
+	}
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var resp APIItemsResponse
+		switch calls {
+		case 1:
+			resp = APIItemsResponse{Items: batch2, LastToken: "tok-2", MoreAvailable: true}
+		case 2:
+			resp = APIItemsResponse{Items: batch3, LastToken: "tok-3", MoreAvailable: false}
+		default:
+			t.Fatalf("unexpected extra fetch (call %d)", calls)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	seed := map[string]Item{}
+	for _, item := range first {
+		seed[item.ItemURL] = item
+	}
+	lister := NewLister("1", server.URL, seed, first, "tok-1", ListerConfig{Method: Sequential})
+	it := newItemIterator(lister, first)
+
+	var got []Item
+	for {
+		item, err := it.Next(context.Background())
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		got = append(got, item)
+	}
+
+	seen := map[string]bool{}
+	for _, item := range got {
+		if seen[item.ItemURL] {
+			t.Fatalf("duplicate ItemURL %q across batches: %+v", item.ItemURL, got)
+		}
+		seen[item.ItemURL] = true
+	}
+
+	wantURLs := map[string]bool{
+		"https://a.bandcamp.com/album/one":   true,
+		"https://b.bandcamp.com/album/two":   true,
+		"https://c.bandcamp.com/album/three": true,
+		"https://d.bandcamp.com/album/four":  true,
+	}
+	if len(got) != len(wantURLs) {
+		t.Fatalf("got %d items, want %d: %+v", len(got), len(wantURLs), got)
+	}
+	for _, item := range got {
+		if !wantURLs[item.ItemURL] {
+			t.Errorf("unexpected item %q", item.ItemURL)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("server got %d calls, want 2", calls)
+	}
+}
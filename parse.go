@@ -0,0 +1,58 @@
+package bandcamp
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// parsePageData walks r as HTML looking for the `#pagedata` element and decodes its `data-blob`
+// attribute into a DataBlob. Unlike a regex match against the raw body, this copes with the
+// attribute appearing in any order and fully HTML-unescapes it (the blob routinely contains
+// entities beyond `&quot;`, e.g. `&amp;` in URLs).
+func parsePageData(r io.Reader) (DataBlob, error) {
+	doc, err := xhtml.Parse(r)
+	if err != nil {
+		return DataBlob{}, err
+	}
+
+	raw, ok := findPageData(doc)
+	if !ok {
+		return DataBlob{}, fmt.Errorf("bandcamp: no #pagedata element found in page")
+	}
+
+	var blob DataBlob
+	if err := json.Unmarshal([]byte(html.UnescapeString(raw)), &blob); err != nil {
+		return DataBlob{}, fmt.Errorf("bandcamp: decoding pagedata blob: %w", err)
+	}
+	return blob, nil
+}
+
+// findPageData recursively searches n for an element with id="pagedata" and returns its
+// data-blob attribute.
+func findPageData(n *xhtml.Node) (string, bool) {
+	if n.Type == xhtml.ElementNode {
+		var id, blob string
+		var hasBlob bool
+		for _, attr := range n.Attr {
+			switch attr.Key {
+			case "id":
+				id = attr.Val
+			case "data-blob":
+				blob, hasBlob = attr.Val, true
+			}
+		}
+		if id == "pagedata" && hasBlob {
+			return blob, true
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if blob, ok := findPageData(c); ok {
+			return blob, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,68 @@
+package bandcamp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePageData(t *testing.T) {
+	tests := []struct {
+		name    string
+		html    string
+		wantFan int
+		wantErr bool
+	}{
+		{
+			name:    "quote-escaped blob",
+			html:    `<html><body><div id="pagedata" data-blob="{&quot;fan_data&quot;:{&quot;fan_id&quot;:42}}"></div></body></html>`,
+			wantFan: 42,
+		},
+		{
+			name:    "fully HTML-escaped blob",
+			html:    `<html><body><div id="pagedata" data-blob="{&quot;fan_data&quot;:{&quot;fan_id&quot;:7},&quot;collection_data&quot;:{&quot;sequence&quot;:[&quot;a&amp;b&quot;]}}"></div></body></html>`,
+			wantFan: 7,
+		},
+		{
+			name:    "no pagedata element",
+			html:    `<html><body><div id="something-else"></div></body></html>`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blob, err := parsePageData(strings.NewReader(tt.html))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parsePageData() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePageData() error = %v", err)
+			}
+			if blob.FanData.ID != tt.wantFan {
+				t.Errorf("FanData.ID = %d, want %d", blob.FanData.ID, tt.wantFan)
+			}
+		})
+	}
+}
+
+func TestItemsInOrder(t *testing.T) {
+	cache := map[string]Item{
+		"1": {ItemURL: "https://a.bandcamp.com/album/one"},
+		"2": {ItemURL: "https://b.bandcamp.com/album/two"},
+	}
+
+	data := ItemData{Sequence: []string{"2", "1"}}
+	items := itemsInOrder(data, cache)
+	if len(items) != 2 || items[0].ItemURL != cache["2"].ItemURL || items[1].ItemURL != cache["1"].ItemURL {
+		t.Fatalf("itemsInOrder(Sequence) = %+v, want items in sequence order", items)
+	}
+
+	data = ItemData{PendingSequence: []string{"1"}}
+	items = itemsInOrder(data, cache)
+	if len(items) != 1 || items[0].ItemURL != cache["1"].ItemURL {
+		t.Fatalf("itemsInOrder(PendingSequence fallback) = %+v, want [%+v]", items, cache["1"])
+	}
+}
@@ -0,0 +1,357 @@
+// Package subsonic implements enough of the Subsonic/OpenSubsonic REST API to let apps like DSub,
+// Symfonium, and play:Sub browse a Bandcamp account's wishlist and purchased collection as if they
+// were a local music library.
+//
+// The purchased collection is exposed as the library's albums, BlobTracks become songs, and the
+// wishlist is exposed as a single synthetic playlist so clients that don't understand "wishlist"
+// still have somewhere to show it.
+package subsonic
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ox/bandcamp"
+)
+
+// apiVersion is the Subsonic protocol version this facade claims to speak.
+const apiVersion = "1.16.1"
+
+// serverVersion is reported in the envelope as this server's own (not Subsonic's) version.
+const serverVersion = "0.1.0"
+
+// envelope is the exact "subsonic-response" wrapper every response (success or failure) is nested
+// in, for both the XML and JSON encodings.
+type envelope struct {
+	XMLName       xml.Name    `json:"-" xml:"subsonic-response"`
+	Status        string      `json:"status" xml:"status,attr"`
+	Version       string      `json:"version" xml:"version,attr"`
+	Type          string      `json:"type" xml:"type,attr"`
+	ServerVersion string      `json:"serverVersion" xml:"serverVersion,attr"`
+	OpenSubsonic  bool        `json:"openSubsonic" xml:"openSubsonic,attr"`
+	Error         *apiError   `json:"error,omitempty" xml:"error,omitempty"`
+	Payload       interface{} `json:"-" xml:"-"`
+}
+
+type apiError struct {
+	Code    int    `json:"code" xml:"code,attr"`
+	Message string `json:"message" xml:"message,attr"`
+}
+
+// jsonEnvelope is what actually gets marshaled to JSON: Subsonic nests the whole envelope (minus
+// XMLName) under a top-level "subsonic-response" key and flattens the payload into it.
+type jsonEnvelope struct {
+	Response map[string]interface{} `json:"subsonic-response"`
+}
+
+func newEnvelope(payload interface{}) envelope {
+	return envelope{
+		Status:        "ok",
+		Version:       apiVersion,
+		Type:          "bandcamp-subsonic",
+		ServerVersion: serverVersion,
+		OpenSubsonic:  true,
+		Payload:       payload,
+	}
+}
+
+func newErrorEnvelope(code int, message string) envelope {
+	e := newEnvelope(nil)
+	e.Status = "failed"
+	e.Error = &apiError{Code: code, Message: message}
+	return e
+}
+
+// writeEnvelope marshals env as XML or JSON depending on the request's "f" query parameter
+// (defaulting to XML, as Subsonic itself does), merging env.Payload's fields into the response.
+func writeEnvelope(w http.ResponseWriter, r *http.Request, env envelope) {
+	if r.URL.Query().Get("f") == "json" {
+		body := map[string]interface{}{
+			"status":        env.Status,
+			"version":       env.Version,
+			"type":          env.Type,
+			"serverVersion": env.ServerVersion,
+			"openSubsonic":  env.OpenSubsonic,
+		}
+		if env.Error != nil {
+			body["error"] = env.Error
+		}
+		mergePayload(body, env.Payload)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(jsonEnvelope{Response: body})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/xml")
+	io.WriteString(w, xml.Header)
+	xml.NewEncoder(w).Encode(env)
+}
+
+func mergePayload(body map[string]interface{}, payload interface{}) {
+	if payload == nil {
+		return
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return
+	}
+	for k, v := range fields {
+		body[k] = v
+	}
+}
+
+// Server adapts a single fan's Bandcamp DataBlob to the Subsonic REST API.
+type Server struct {
+	Fan  string
+	Blob bandcamp.DataBlob
+
+	// SessionCookie, if set, is sent when resolving purchased-item download URLs for stream.
+	SessionCookie string
+}
+
+// NewServer builds a Server for fan backed by blob, which should be the DataBlob parsed from the
+// fan's wishlist or collection page.
+func NewServer(fan string, blob bandcamp.DataBlob) *Server {
+	return &Server{Fan: fan, Blob: blob}
+}
+
+// Mux returns the set of Subsonic routes this Server handles. Subsonic clients call every endpoint
+// under both "/rest/<name>" and "/rest/<name>.view"; both are registered.
+func (s *Server) Mux() *http.ServeMux {
+	mux := http.NewServeMux()
+	routes := map[string]http.HandlerFunc{
+		"ping":            s.handlePing,
+		"getMusicFolders": s.handleGetMusicFolders,
+		"getIndexes":      s.handleGetIndexes,
+		"getAlbumList2":   s.handleGetAlbumList2,
+		"getAlbum":        s.handleGetAlbum,
+		"getSong":         s.handleGetSong,
+		"getPlaylists":    s.handleGetPlaylists,
+		"stream":          s.handleStream,
+		"getCoverArt":     s.handleGetCoverArt,
+	}
+	for name, handler := range routes {
+		mux.HandleFunc("/rest/"+name, handler)
+		mux.HandleFunc("/rest/"+name+".view", handler)
+	}
+	return mux
+}
+
+func (s *Server) handlePing(w http.ResponseWriter, r *http.Request) {
+	writeEnvelope(w, r, newEnvelope(nil))
+}
+
+func (s *Server) handleGetMusicFolders(w http.ResponseWriter, r *http.Request) {
+	writeEnvelope(w, r, newEnvelope(map[string]interface{}{
+		"musicFolders": map[string]interface{}{
+			"musicFolder": []map[string]interface{}{
+				{"id": 1, "name": s.Fan + "'s collection"},
+			},
+		},
+	}))
+}
+
+// albums maps the purchased collection, ordered by CollectionData.Sequence, to Subsonic albums.
+func (s *Server) albums() []map[string]interface{} {
+	albums := make([]map[string]interface{}, 0, len(s.Blob.ItemCache.Collection))
+	for _, id := range s.Blob.CollectionData.Sequence {
+		item, ok := s.Blob.ItemCache.Collection[id]
+		if !ok {
+			continue
+		}
+		tracks := tracksForAlbum(s.Blob.TrackList, id)
+		artist := ""
+		if len(tracks) > 0 {
+			artist = tracks[0].BandName
+		}
+		albums = append(albums, map[string]interface{}{
+			"id":        id,
+			"name":      item.ItemURL,
+			"coverArt":  "al-" + id,
+			"created":   item.Added,
+			"songCount": len(tracks),
+			"artist":    artist,
+		})
+	}
+	return albums
+}
+
+// tracksForAlbum returns the BlobTracks belonging to albumID, joining on BlobTrack.AlbumID rather
+// than relying on any positional correspondence between TrackList and CollectionData.Sequence.
+func tracksForAlbum(tracks []bandcamp.BlobTrack, albumID string) []bandcamp.BlobTrack {
+	id, err := strconv.Atoi(albumID)
+	if err != nil {
+		return nil
+	}
+	var matched []bandcamp.BlobTrack
+	for _, track := range tracks {
+		if track.AlbumID == id {
+			matched = append(matched, track)
+		}
+	}
+	return matched
+}
+
+func (s *Server) handleGetIndexes(w http.ResponseWriter, r *http.Request) {
+	byLetter := map[string][]map[string]interface{}{}
+	for _, album := range s.albums() {
+		name, _ := album["artist"].(string)
+		letter := "#"
+		if name != "" {
+			letter = string([]rune(name)[0])
+		}
+		byLetter[letter] = append(byLetter[letter], map[string]interface{}{
+			"id":   album["id"],
+			"name": name,
+		})
+	}
+	indexes := make([]map[string]interface{}, 0, len(byLetter))
+	for letter, artists := range byLetter {
+		indexes = append(indexes, map[string]interface{}{
+			"name":   letter,
+			"artist": artists,
+		})
+	}
+	writeEnvelope(w, r, newEnvelope(map[string]interface{}{
+		"indexes": map[string]interface{}{"index": indexes},
+	}))
+}
+
+func (s *Server) handleGetAlbumList2(w http.ResponseWriter, r *http.Request) {
+	writeEnvelope(w, r, newEnvelope(map[string]interface{}{
+		"albumList2": map[string]interface{}{"album": s.albums()},
+	}))
+}
+
+func (s *Server) handleGetAlbum(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	item, ok := s.Blob.ItemCache.Collection[id]
+	if !ok {
+		writeEnvelope(w, r, newErrorEnvelope(70, "album not found"))
+		return
+	}
+	writeEnvelope(w, r, newEnvelope(map[string]interface{}{
+		"album": map[string]interface{}{
+			"id":   id,
+			"name": item.ItemURL,
+			"song": s.songsForAlbum(id),
+		},
+	}))
+}
+
+func (s *Server) songsForAlbum(albumID string) []map[string]interface{} {
+	tracks := tracksForAlbum(s.Blob.TrackList, albumID)
+	songs := make([]map[string]interface{}, 0, len(tracks))
+	for i, track := range tracks {
+		songs = append(songs, songEntry(albumID, i, track))
+	}
+	return songs
+}
+
+// songEntry builds a Subsonic song entry for the track at index idx within albumID's track list,
+// using the same "<albumID>-<idx>" id songsForAlbum and handleGetSong both key off of.
+func songEntry(albumID string, idx int, track bandcamp.BlobTrack) map[string]interface{} {
+	return map[string]interface{}{
+		"id":     albumID + "-" + strconv.Itoa(idx),
+		"title":  track.Title,
+		"artist": track.BandName,
+		"album":  albumID,
+		"parent": albumID,
+	}
+}
+
+func (s *Server) handleGetSong(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	albumID, idxStr, ok := strings.Cut(id, "-")
+	if !ok {
+		writeEnvelope(w, r, newErrorEnvelope(70, "song not found"))
+		return
+	}
+	idx, err := strconv.Atoi(idxStr)
+	if err != nil {
+		writeEnvelope(w, r, newErrorEnvelope(70, "song not found"))
+		return
+	}
+	tracks := tracksForAlbum(s.Blob.TrackList, albumID)
+	if idx < 0 || idx >= len(tracks) {
+		writeEnvelope(w, r, newErrorEnvelope(70, "song not found"))
+		return
+	}
+	writeEnvelope(w, r, newEnvelope(map[string]interface{}{
+		"song": songEntry(albumID, idx, tracks[idx]),
+	}))
+}
+
+// handleGetPlaylists exposes the wishlist as a single synthetic playlist, since Subsonic has no
+// native concept of a Bandcamp wishlist.
+func (s *Server) handleGetPlaylists(w http.ResponseWriter, r *http.Request) {
+	writeEnvelope(w, r, newEnvelope(map[string]interface{}{
+		"playlists": map[string]interface{}{
+			"playlist": []map[string]interface{}{
+				{
+					"id":        "wishlist",
+					"name":      s.Fan + "'s wishlist",
+					"songCount": len(s.Blob.ItemCache.Wishlist),
+				},
+			},
+		},
+	}))
+}
+
+// previewURLExp matches the streamable MP3 preview URL embedded in an album page's trackinfo blob.
+var previewURLExp = regexp.MustCompile(`"mp3-128":"(https:[^"]+)"`)
+
+// handleStream proxies to the streamable MP3 preview URL found on the item's album page. Purchased
+// items would use the authenticated download URL instead if SessionCookie is set, but resolving
+// that requires walking the purchase's redownload page, which isn't implemented yet.
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	item, ok := s.Blob.ItemCache.Collection[id]
+	if !ok {
+		item, ok = s.Blob.ItemCache.Wishlist[id]
+	}
+	if !ok {
+		writeEnvelope(w, r, newErrorEnvelope(70, "song/album not found"))
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodGet, item.ItemURL, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if s.SessionCookie != "" {
+		req.Header.Set("Cookie", s.SessionCookie)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	page, err := io.ReadAll(resp.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	match := previewURLExp.FindSubmatch(page)
+	if match == nil {
+		http.Error(w, "no stream URL found on album page", http.StatusNotFound)
+		return
+	}
+	http.Redirect(w, r, string(match[1]), http.StatusFound)
+}
+
+func (s *Server) handleGetCoverArt(w http.ResponseWriter, r *http.Request) {
+	http.NotFound(w, r)
+}
@@ -0,0 +1,121 @@
+package subsonic
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ox/bandcamp"
+)
+
+func testServer() *Server {
+	blob := bandcamp.DataBlob{
+		TrackList: []bandcamp.BlobTrack{
+			{BandName: "Space Band", Title: "Orbit", AlbumID: 100},
+			{BandName: "Space Band", Title: "Reentry", AlbumID: 100},
+			{BandName: "Moon Band", Title: "Tide", AlbumID: 200},
+		},
+		CollectionData: bandcamp.ItemData{Sequence: []string{"100", "200"}},
+	}
+	blob.ItemCache.Collection = map[string]bandcamp.Item{
+		"100": {ItemURL: "https://spaceband.bandcamp.com/album/orbit", Added: "01 Jan 2024 00:00:00 GMT"},
+		"200": {ItemURL: "https://moonband.bandcamp.com/album/tide", Added: "02 Jan 2024 00:00:00 GMT"},
+	}
+	return NewServer("space-llama", blob)
+}
+
+func TestTracksForAlbum(t *testing.T) {
+	s := testServer()
+
+	tests := []struct {
+		albumID    string
+		wantTitles []string
+	}{
+		{albumID: "100", wantTitles: []string{"Orbit", "Reentry"}},
+		{albumID: "200", wantTitles: []string{"Tide"}},
+		{albumID: "not-a-number", wantTitles: nil},
+		{albumID: "999", wantTitles: nil},
+	}
+
+	for _, tt := range tests {
+		tracks := tracksForAlbum(s.Blob.TrackList, tt.albumID)
+		if len(tracks) != len(tt.wantTitles) {
+			t.Fatalf("tracksForAlbum(%q) = %d tracks, want %d", tt.albumID, len(tracks), len(tt.wantTitles))
+		}
+		for i, track := range tracks {
+			if track.Title != tt.wantTitles[i] {
+				t.Errorf("tracksForAlbum(%q)[%d].Title = %q, want %q", tt.albumID, i, track.Title, tt.wantTitles[i])
+			}
+		}
+	}
+}
+
+func TestAlbumsDoNotCrossContaminate(t *testing.T) {
+	s := testServer()
+
+	albums := s.albums()
+	if len(albums) != 2 {
+		t.Fatalf("albums() returned %d albums, want 2", len(albums))
+	}
+	for _, album := range albums {
+		id := album["id"].(string)
+		wantArtist := map[string]string{"100": "Space Band", "200": "Moon Band"}[id]
+		if album["artist"] != wantArtist {
+			t.Errorf("album %s artist = %v, want %q", id, album["artist"], wantArtist)
+		}
+		wantSongCount := map[string]int{"100": 2, "200": 1}[id]
+		if album["songCount"] != wantSongCount {
+			t.Errorf("album %s songCount = %v, want %d", id, album["songCount"], wantSongCount)
+		}
+	}
+}
+
+func TestHandleGetAlbumJSON(t *testing.T) {
+	s := testServer()
+	req := httptest.NewRequest("GET", "/rest/getAlbum?id=100&f=json", nil)
+	w := httptest.NewRecorder()
+
+	s.Mux().ServeHTTP(w, req)
+
+	var body struct {
+		Response struct {
+			Status string `json:"status"`
+			Album  struct {
+				Song []map[string]interface{} `json:"song"`
+			} `json:"album"`
+		} `json:"subsonic-response"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding response: %v (body: %s)", err, w.Body.String())
+	}
+	if body.Response.Status != "ok" {
+		t.Fatalf("status = %q, want ok", body.Response.Status)
+	}
+	if len(body.Response.Album.Song) != 2 {
+		t.Fatalf("album 100 returned %d songs, want 2", len(body.Response.Album.Song))
+	}
+}
+
+func TestHandleGetSongJSON(t *testing.T) {
+	s := testServer()
+	req := httptest.NewRequest("GET", "/rest/getSong?id=100-1&f=json", nil)
+	w := httptest.NewRecorder()
+
+	s.Mux().ServeHTTP(w, req)
+
+	var body struct {
+		Response struct {
+			Status string                 `json:"status"`
+			Song   map[string]interface{} `json:"song"`
+		} `json:"subsonic-response"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding response: %v (body: %s)", err, w.Body.String())
+	}
+	if body.Response.Status != "ok" {
+		t.Fatalf("status = %q, want ok", body.Response.Status)
+	}
+	if body.Response.Song["title"] != "Reentry" || body.Response.Song["artist"] != "Space Band" {
+		t.Errorf("song = %+v, want title Reentry by Space Band", body.Response.Song)
+	}
+}